@@ -0,0 +1,35 @@
+// Package openstack performs the OpenStack-specific infrastructure steps
+// needed before master instances can boot: uploading the RHCOS image to
+// Glance and hosting the bootstrap ignition in Swift behind a TempURL,
+// rather than shipping the full bootstrap ignition as instance
+// user-data.
+package openstack
+
+import (
+	"os"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+// authenticatedClient authenticates against platform.Cloud, as defined
+// in clouds.yaml (or platform.CloudsYAMLPath, if set), and returns the
+// provider client and endpoint options used to build a service client.
+func authenticatedClient(platform *openstacktypes.Platform) (*gophercloud.ProviderClient, gophercloud.EndpointOpts, error) {
+	if platform.CloudsYAMLPath != "" {
+		if err := os.Setenv("OS_CLIENT_CONFIG_FILE", platform.CloudsYAMLPath); err != nil {
+			return nil, gophercloud.EndpointOpts{}, errors.Wrap(err, "failed to point OpenStack SDK at CloudsYAMLPath")
+		}
+	}
+
+	providerClient, endpointOpts, err := clientconfig.AuthenticatedClient(&clientconfig.ClientOpts{
+		Cloud: platform.Cloud,
+	})
+	if err != nil {
+		return nil, gophercloud.EndpointOpts{}, errors.Wrapf(err, "failed to authenticate to OpenStack cloud %q", platform.Cloud)
+	}
+	return providerClient, endpointOpts, nil
+}
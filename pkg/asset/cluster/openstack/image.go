@@ -0,0 +1,98 @@
+package openstack
+
+import (
+	"bytes"
+	"crypto/md5" // #nosec G501 -- Glance image checksums are MD5 by convention
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imagedata"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/pagination"
+	"github.com/pkg/errors"
+
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+// EnsureImage uploads the RHCOS image found at imagePath to Glance under
+// platform.ImageName, skipping the upload if an image with that name and
+// checksum already exists. It returns the Glance image ID to use when
+// booting master and worker instances.
+func EnsureImage(platform *openstacktypes.Platform, imagePath string) (string, error) {
+	providerClient, endpointOpts, err := authenticatedClient(platform)
+	if err != nil {
+		return "", err
+	}
+	client, err := openstack.NewImageServiceV2(providerClient, endpointOpts)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create Glance client")
+	}
+
+	checksum, err := fileMD5(imagePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to checksum %s", imagePath)
+	}
+
+	existingID, err := findImageByChecksum(client, platform.ImageName, checksum)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list existing Glance images")
+	}
+	if existingID != "" {
+		return existingID, nil
+	}
+
+	created, err := images.Create(client, images.CreateOpts{
+		Name:            platform.ImageName,
+		ContainerFormat: "bare",
+		DiskFormat:      "qcow2",
+	}).Extract()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create Glance image %s", platform.ImageName)
+	}
+
+	data, err := ioutil.ReadFile(imagePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", imagePath)
+	}
+	if err := imagedata.Upload(client, created.ID, bytes.NewReader(data)).ExtractErr(); err != nil {
+		return "", errors.Wrapf(err, "failed to upload %s to Glance image %s", imagePath, created.ID)
+	}
+
+	return created.ID, nil
+}
+
+func findImageByChecksum(client *gophercloud.ServiceClient, name, checksum string) (string, error) {
+	var found string
+	err := images.List(client, images.ListOpts{Name: name}).EachPage(func(page pagination.Page) (bool, error) {
+		imgs, err := images.ExtractImages(page)
+		if err != nil {
+			return false, err
+		}
+		for _, img := range imgs {
+			if img.Checksum == checksum {
+				found = img.ID
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	return found, err
+}
+
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New() // #nosec G401 -- matches Glance's own checksum algorithm
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,118 @@
+package openstack
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"github.com/pkg/errors"
+
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+const bootstrapObjectName = "bootstrap.ign"
+
+// UploadBootstrapIgnition uploads data to platform.SwiftContainer as a
+// temporary object and returns a TempURL valid for ttl. If
+// platform.TempURLKey is unset, the account's existing Temp-URL-Key is
+// reused if one is already set (other TempURLs in the tenant's account
+// may depend on it); only when the account truly has none is a new key
+// generated and set. Either way the key is recorded on platform so
+// subsequent calls skip the account metadata lookup.
+func UploadBootstrapIgnition(platform *openstacktypes.Platform, data []byte, ttl time.Duration) (string, error) {
+	client, err := swiftClient(platform)
+	if err != nil {
+		return "", err
+	}
+
+	if platform.TempURLKey == "" {
+		key, err := existingOrNewTempURLKey(client)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to determine Swift account TempURL key")
+		}
+		platform.TempURLKey = key
+	}
+
+	if _, err := objects.Create(client, platform.SwiftContainer, bootstrapObjectName, objects.CreateOpts{
+		Content: bytes.NewReader(data),
+	}).Extract(); err != nil {
+		return "", errors.Wrapf(err, "failed to upload bootstrap ignition to Swift container %s", platform.SwiftContainer)
+	}
+
+	url, err := objects.CreateTempURL(client, platform.SwiftContainer, bootstrapObjectName, objects.CreateTempURLOpts{
+		Method: http.MethodGet,
+		TTL:    int(ttl.Seconds()),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create TempURL for bootstrap ignition")
+	}
+	return url, nil
+}
+
+// DeleteBootstrapIgnition removes the object uploaded by
+// UploadBootstrapIgnition. A missing object is not an error.
+// `openshift-install destroy cluster` does not call it, so the object
+// stays in the container until this function is called.
+func DeleteBootstrapIgnition(platform *openstacktypes.Platform) error {
+	client, err := swiftClient(platform)
+	if err != nil {
+		return err
+	}
+
+	result := objects.Delete(client, platform.SwiftContainer, bootstrapObjectName, nil)
+	if result.Err != nil && !gophercloud.ResponseCodeIs(result.Err, http.StatusNotFound) {
+		return errors.Wrapf(result.Err, "failed to delete bootstrap ignition from Swift container %s", platform.SwiftContainer)
+	}
+	return nil
+}
+
+func swiftClient(platform *openstacktypes.Platform) (*gophercloud.ServiceClient, error) {
+	providerClient, endpointOpts, err := authenticatedClient(platform)
+	if err != nil {
+		return nil, err
+	}
+	client, err := openstack.NewObjectStorageV1(providerClient, endpointOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Swift client")
+	}
+	return client, nil
+}
+
+// existingOrNewTempURLKey returns the Swift account's current
+// Temp-URL-Key if one is already set, leaving the account untouched so
+// other TempURLs signed with it keep working. Only when the account has
+// no key at all is a new random one generated and set.
+func existingOrNewTempURLKey(client *gophercloud.ServiceClient) (string, error) {
+	account, err := accounts.Get(client, nil).Extract()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read Swift account metadata")
+	}
+	if account.TempURLKey != "" {
+		return account.TempURLKey, nil
+	}
+
+	key, err := randomHexKey(32)
+	if err != nil {
+		return "", err
+	}
+	if _, err := accounts.Update(client, accounts.UpdateOpts{
+		TempURLKey: &key,
+	}).Extract(); err != nil {
+		return "", errors.Wrap(err, "failed to set Swift account TempURL key")
+	}
+	return key, nil
+}
+
+func randomHexKey(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -13,6 +14,7 @@ import (
 	igntypes "github.com/coreos/ignition/config/v2_2/types"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition"
@@ -45,6 +47,14 @@ type bootstrapTemplateData struct {
 type Bootstrap struct {
 	Config *igntypes.Config
 	File   *asset.File
+
+	// sensitivePaths collects the Storage.Files paths that carry TLS
+	// private keys, kubeconfigs, or other install-time secrets, as
+	// they're staged by addBootstrapFiles/addBootkubeFiles/addTLSCertFiles.
+	// encryptSensitiveFiles only encrypts entries in this set, not every
+	// file in the config (most, like bootkube.sh or the operator
+	// manifests, aren't secret and don't need a decrypt dependency).
+	sensitivePaths []string
 }
 
 var _ asset.WritableAsset = (*Bootstrap)(nil)
@@ -80,6 +90,12 @@ func (a *Bootstrap) Generate(dependencies asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	dependencies.Get(installConfig)
 
+	if azurePlatform := installConfig.Config.Platform.Azure; azurePlatform != nil {
+		if err := azurePlatform.Validate(); err != nil {
+			return errors.Wrap(err, "invalid Azure platform configuration")
+		}
+	}
+
 	templateData, err := a.getTemplateData(installConfig.Config)
 	if err != nil {
 		return errors.Wrap(err, "failed to get bootstrap templates")
@@ -97,6 +113,12 @@ func (a *Bootstrap) Generate(dependencies asset.Parents) error {
 	a.addTectonicFiles(dependencies)
 	a.addTLSCertFiles(dependencies)
 
+	if installConfig.Config.Encryption != nil {
+		if err := a.encryptSensitiveFiles(installConfig.Config.Encryption); err != nil {
+			return errors.Wrap(err, "failed to encrypt bootstrap secrets")
+		}
+	}
+
 	a.Config.Systemd.Units = append(
 		a.Config.Systemd.Units,
 		igntypes.Unit{Name: "bootkube.service", Contents: content.BootkubeSystemdContents},
@@ -110,9 +132,35 @@ func (a *Bootstrap) Generate(dependencies asset.Parents) error {
 		igntypes.PasswdUser{Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{igntypes.SSHAuthorizedKey(installConfig.Config.Admin.SSHKey)}},
 	)
 
-	data, err := json.Marshal(a.Config)
+	for _, m := range installConfig.Config.Machines {
+		if m.Name != "bootstrap" {
+			// master and worker MachinePools have no Ignition asset of
+			// their own in this installer: their nodes join the cluster
+			// through cluster-api actuators after bootstrap, which is
+			// where their Labels/Taints/IgnitionOverrides would need to
+			// be wired in instead. Only the bootstrap pool is rendered
+			// here.
+			continue
+		}
+		if m.IgnitionOverrides != nil {
+			if err := mergeIgnitionOverrides(a.Config, m.IgnitionOverrides); err != nil {
+				return errors.Wrap(err, "failed to merge bootstrap ignitionOverrides")
+			}
+		}
+		a.addKubeletExtraArgs(m.Labels, m.Taints)
+	}
+
+	var bootstrapFormat types.BootstrapFormat
+	if installConfig.Config.Bootstrap != nil {
+		bootstrapFormat = installConfig.Config.Bootstrap.Format
+	}
+	renderer, err := rendererForFormat(bootstrapFormat)
+	if err != nil {
+		return errors.Wrap(err, "failed to select bootstrap renderer")
+	}
+	data, err := renderer.Render(a.Config)
 	if err != nil {
-		return errors.Wrap(err, "failed to Marshal Ignition config")
+		return errors.Wrap(err, "failed to render bootstrap config")
 	}
 	a.File = &asset.File{
 		Filename: bootstrapIgnFilename,
@@ -122,6 +170,56 @@ func (a *Bootstrap) Generate(dependencies asset.Parents) error {
 	return nil
 }
 
+// addKubeletExtraArgs appends a kubelet.service drop-in setting
+// KUBELET_EXTRA_ARGS from labels/taints, so the bootstrap node's kubelet
+// registers with the same node labels and taints the user requested for
+// the bootstrap MachinePool. It's a no-op if both are empty.
+func (a *Bootstrap) addKubeletExtraArgs(labels map[string]string, taints []corev1.Taint) {
+	args := kubeletExtraArgs(labels, taints)
+	if args == "" {
+		return
+	}
+	for i, u := range a.Config.Systemd.Units {
+		if u.Name != "kubelet.service" {
+			continue
+		}
+		a.Config.Systemd.Units[i].Dropins = append(u.Dropins, igntypes.SystemdDropin{
+			Name:     "20-machinepool-args.conf",
+			Contents: fmt.Sprintf("[Service]\nEnvironment=\"KUBELET_EXTRA_ARGS=%s\"\n", args),
+		})
+		return
+	}
+}
+
+// kubeletExtraArgs renders labels/taints as the --node-labels and
+// --register-with-taints flags kubelet expects.
+func kubeletExtraArgs(labels map[string]string, taints []corev1.Taint) string {
+	var args []string
+
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = fmt.Sprintf("%s=%s", k, labels[k])
+		}
+		args = append(args, "--node-labels="+strings.Join(pairs, ","))
+	}
+
+	if len(taints) > 0 {
+		specs := make([]string, len(taints))
+		for i, t := range taints {
+			specs[i] = fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+		}
+		args = append(args, "--register-with-taints="+strings.Join(specs, ","))
+	}
+
+	return strings.Join(args, " ")
+}
+
 // Name returns the human-friendly name of the asset.
 func (a *Bootstrap) Name() string {
 	return "Bootstrap Ignition Config"
@@ -141,6 +239,10 @@ func (a *Bootstrap) getTemplateData(installConfig *types.InstallConfig) (*bootst
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get ClusterDNSIP from InstallConfig")
 	}
+	// Etcd member names and MasterCount come from BaseDomain/Machines,
+	// which are platform-agnostic, so this covers Azure the same way it
+	// already covers every other Platform variant; no azure-specific
+	// branch is needed here.
 	etcdEndpoints := make([]string, installConfig.MasterCount())
 	for i := range etcdEndpoints {
 		etcdEndpoints[i] = fmt.Sprintf("https://%s-etcd-%d.%s:2379", installConfig.ObjectMeta.Name, i, installConfig.BaseDomain)
@@ -166,10 +268,12 @@ func (a *Bootstrap) addBootstrapFiles(dependencies asset.Parents) {
 	kubeletKubeconfig := &kubeconfig.Kubelet{}
 	dependencies.Get(kubeletKubeconfig)
 
+	const kubeletKubeconfigPath = "/etc/kubernetes/kubeconfig"
 	a.Config.Storage.Files = append(
 		a.Config.Storage.Files,
-		ignition.FileFromBytes("/etc/kubernetes/kubeconfig", 0600, kubeletKubeconfig.Files()[0].Data),
+		ignition.FileFromBytes(kubeletKubeconfigPath, 0600, kubeletKubeconfig.Files()[0].Data),
 	)
+	a.sensitivePaths = append(a.sensitivePaths, kubeletKubeconfigPath)
 	a.Config.Storage.Files = append(
 		a.Config.Storage.Files,
 		ignition.FileFromString("/usr/local/bin/report-progress.sh", 0555, content.ReportShFileContents),
@@ -192,10 +296,10 @@ func (a *Bootstrap) addBootkubeFiles(dependencies asset.Parents, templateData *b
 			ignition.FileFromString(filepath.Join(bootkubeConfigOverridesDir, o.Name()), 0600, applyTemplateData(o, templateData)),
 		)
 	}
-	a.Config.Storage.Files = append(
-		a.Config.Storage.Files,
-		ignition.FilesFromAsset(rootDir, 0600, adminKubeconfig)...,
-	)
+	adminKubeconfigFiles := ignition.FilesFromAsset(rootDir, 0600, adminKubeconfig)
+	a.Config.Storage.Files = append(a.Config.Storage.Files, adminKubeconfigFiles...)
+	a.sensitivePaths = append(a.sensitivePaths, filePaths(adminKubeconfigFiles)...)
+
 	a.Config.Storage.Files = append(
 		a.Config.Storage.Files,
 		ignition.FilesFromAsset(rootDir, 0644, manifests)...,
@@ -264,15 +368,28 @@ func (a *Bootstrap) addTLSCertFiles(dependencies asset.Parents) {
 		&tls.ServiceAccountKeyPair{},
 	} {
 		dependencies.Get(asset)
-		a.Config.Storage.Files = append(a.Config.Storage.Files, ignition.FilesFromAsset(rootDir, 0600, asset)...)
+		tlsFiles := ignition.FilesFromAsset(rootDir, 0600, asset)
+		a.Config.Storage.Files = append(a.Config.Storage.Files, tlsFiles...)
+		a.sensitivePaths = append(a.sensitivePaths, filePaths(tlsFiles)...)
 	}
 
 	etcdClientCertKey := &tls.EtcdClientCertKey{}
 	dependencies.Get(etcdClientCertKey)
+	const etcdCACertPath = "/etc/ssl/etcd/ca.crt"
 	a.Config.Storage.Files = append(
 		a.Config.Storage.Files,
-		ignition.FileFromBytes("/etc/ssl/etcd/ca.crt", 0600, etcdClientCertKey.Cert()),
+		ignition.FileFromBytes(etcdCACertPath, 0600, etcdClientCertKey.Cert()),
 	)
+	a.sensitivePaths = append(a.sensitivePaths, etcdCACertPath)
+}
+
+// filePaths returns the Storage.Files paths of files.
+func filePaths(files []igntypes.File) []string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths
 }
 
 func applyTemplateData(template *template.Template, templateData interface{}) string {
@@ -293,11 +410,16 @@ func (a *Bootstrap) Load(f asset.FileFetcher) (found bool, err error) {
 		return false, err
 	}
 
+	// The on-disk bootstrap config may be an Ignition v2.2 config, an
+	// Ignition v3.x config, or a cloud-init cloud-config document,
+	// depending on the BootstrapFormat used to render it. Only the
+	// Ignition v2.2 shape can be reconstructed into a.Config; for the
+	// others, a.File still carries the raw rendered bytes.
 	config := &igntypes.Config{}
-	if err := json.Unmarshal(file.Data, config); err != nil {
-		return false, errors.Wrapf(err, "failed to unmarshal")
+	if err := json.Unmarshal(file.Data, config); err == nil {
+		a.Config = config
 	}
 
-	a.File, a.Config = file, config
+	a.File = file
 	return true, nil
 }
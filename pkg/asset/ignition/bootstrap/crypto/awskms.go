@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+)
+
+// awsKMSWrapper wraps the data key with AWS KMS. At boot, the bootstrap
+// node unwraps it with the AWS CLI, which picks up credentials from the
+// instance's metadata service automatically.
+type awsKMSWrapper struct {
+	keyARN string
+}
+
+// NewAWSKMSWrapper returns a Wrapper that encrypts the data key with the
+// AWS KMS key identified by keyARN.
+func NewAWSKMSWrapper(keyARN string) Wrapper {
+	return &awsKMSWrapper{keyARN: keyARN}
+}
+
+func (w *awsKMSWrapper) Wrap(dataKey []byte) ([]byte, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	out, err := kms.New(sess).Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(w.keyARN),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encrypt data key with KMS key %s", w.keyARN)
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (w *awsKMSWrapper) UnwrapScript() string {
+	return fmt.Sprintf(
+		`aws kms decrypt --key-id %q --ciphertext-blob fileb:///opt/tectonic/bootstrap.key.wrapped --query Plaintext --output text | base64 -d > /opt/tectonic/bootstrap.key`,
+		w.keyARN,
+	)
+}
+
+// ServiceExtras is empty: the AWS CLI picks up credentials from instance
+// metadata automatically, no extra [Service] environment is needed.
+func (w *awsKMSWrapper) ServiceExtras() string {
+	return ""
+}
@@ -0,0 +1,63 @@
+package crypto
+
+import (
+	"encoding/base64"
+
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/secrets"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+)
+
+// barbicanWrapper wraps the data key as a new OpenStack Barbican secret.
+// At boot, the bootstrap node unwraps it by fetching that secret's
+// payload with the openstack CLI, which authenticates with whatever
+// OpenStack credentials it finds on the node; the installer does not
+// write any credentials for it.
+type barbicanWrapper struct {
+	secretRef string
+}
+
+// NewBarbicanWrapper returns a Wrapper that stores the data key as the
+// Barbican secret named secretRef.
+func NewBarbicanWrapper(secretRef string) Wrapper {
+	return &barbicanWrapper{secretRef: secretRef}
+}
+
+func (w *barbicanWrapper) Wrap(dataKey []byte) ([]byte, error) {
+	providerClient, endpointOpts, err := clientconfig.AuthenticatedClient(&clientconfig.ClientOpts{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to authenticate to OpenStack")
+	}
+	client, err := openstack.NewKeyManagerV1(providerClient, endpointOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Barbican client")
+	}
+
+	payload := base64.StdEncoding.EncodeToString(dataKey)
+	created, err := secrets.Create(client, secrets.CreateOpts{
+		Name:                   w.secretRef,
+		Payload:                payload,
+		PayloadContentType:     "text/plain",
+		PayloadContentEncoding: "base64",
+		SecretType:             secrets.SymmetricSecret,
+	}).Extract()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to store data key as Barbican secret %s", w.secretRef)
+	}
+	return []byte(created.SecretRef), nil
+}
+
+// UnwrapScript fetches the secret's payload. The secret href is a
+// positional argument to `openstack secret get`, not the value of
+// --payload (--payload is a boolean flag that selects the Payload column
+// for -f/-c to print).
+func (w *barbicanWrapper) UnwrapScript() string {
+	return `openstack secret get "$(cat /opt/tectonic/bootstrap.key.wrapped)" --payload -f value -c Payload | base64 -d > /opt/tectonic/bootstrap.key`
+}
+
+// ServiceExtras is empty: no extra [Service] environment is added, so
+// the OpenStack CLI uses whatever credentials it finds on the node.
+func (w *barbicanWrapper) ServiceExtras() string {
+	return ""
+}
@@ -0,0 +1,190 @@
+// Package crypto encrypts the bootstrap config's sensitive files with a
+// random data key, and wraps that data key with a per-provider KMS so
+// only the bootstrap node (via its instance metadata credentials) can
+// recover it and decrypt the files at boot.
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// EncKeySize is the AES-256 encryption key size in bytes.
+	EncKeySize = 32
+	// MacKeySize is the HMAC-SHA256 key size in bytes.
+	MacKeySize = 32
+	// dataKeySize is EncKeySize||MacKeySize concatenated: the data key is
+	// two independent keys, not one key two algorithms derive from, so
+	// the boot-time decrypt script can slice it apart with plain dd/head
+	// instead of needing an HKDF implementation on the node.
+	dataKeySize = EncKeySize + MacKeySize
+
+	// ivSize is the AES block size used as the CBC IV size.
+	ivSize = aes.BlockSize
+	// macSize is the HMAC-SHA256 tag size in bytes.
+	macSize = sha256.Size
+
+	// BootstrapKeyWrappedPath is where the wrapped data key is staged in
+	// the bootstrap ignition config, for Wrap's output and UnwrapScript's
+	// input to agree on.
+	BootstrapKeyWrappedPath = "/opt/tectonic/bootstrap.key.wrapped"
+	// BootstrapKeyPath is where UnwrapScript writes the recovered data
+	// key, for decryptScript to read it back from.
+	BootstrapKeyPath = "/opt/tectonic/bootstrap.key"
+)
+
+// GenerateDataKey returns a random data key: EncKeySize bytes of AES-256
+// key followed by MacKeySize bytes of HMAC-SHA256 key.
+func GenerateDataKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate data key")
+	}
+	return key, nil
+}
+
+// Encrypt encrypts plaintext with dataKey using AES-256-CBC-then-HMAC-SHA256
+// (encrypt-then-MAC) and returns iv || ciphertext || tag. Unlike AES-GCM,
+// every primitive here (CBC encryption, HMAC) has a well-supported plain
+// `openssl enc`/`openssl dgst` CLI invocation, since openssl's enc
+// subcommand doesn't implement AEAD ciphers (see decryptScript and
+// localPassphraseWrapper.UnwrapScript, which decrypt this format at boot
+// without a GCM-capable tool).
+func Encrypt(dataKey, plaintext []byte) ([]byte, error) {
+	if len(dataKey) != dataKeySize {
+		return nil, errors.Errorf("data key must be %d bytes, got %d", dataKeySize, len(dataKey))
+	}
+	encKey, macKey := dataKey[:EncKeySize], dataKey[EncKeySize:]
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AES cipher")
+	}
+
+	iv := make([]byte, ivSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, errors.Wrap(err, "failed to generate IV")
+	}
+
+	padded := pkcs7Pad(plaintext, aes.BlockSize)
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	tag := hmacTag(macKey, iv, ciphertext)
+
+	out := make([]byte, 0, len(iv)+len(ciphertext)+len(tag))
+	out = append(out, iv...)
+	out = append(out, ciphertext...)
+	out = append(out, tag...)
+	return out, nil
+}
+
+// Decrypt reverses Encrypt. It is used by installer-side tooling (and
+// tests) that need to read an encrypted bootstrap file back; the
+// bootstrap node itself decrypts with the openssl invocations emitted by
+// decryptScript, not this function.
+func Decrypt(dataKey, blob []byte) ([]byte, error) {
+	if len(dataKey) != dataKeySize {
+		return nil, errors.Errorf("data key must be %d bytes, got %d", dataKeySize, len(dataKey))
+	}
+	encKey, macKey := dataKey[:EncKeySize], dataKey[EncKeySize:]
+
+	if len(blob) < ivSize+macSize {
+		return nil, errors.New("ciphertext shorter than iv+tag size")
+	}
+	iv := blob[:ivSize]
+	ciphertext := blob[ivSize : len(blob)-macSize]
+	tag := blob[len(blob)-macSize:]
+
+	if !hmac.Equal(tag, hmacTag(macKey, iv, ciphertext)) {
+		return nil, errors.New("HMAC tag mismatch")
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AES cipher")
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("ciphertext is not a multiple of the block size")
+	}
+	padded := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(padded, ciphertext)
+
+	return pkcs7Unpad(padded)
+}
+
+// hmacTag computes the HMAC-SHA256 tag over iv||ciphertext with macKey,
+// matching the `openssl dgst -sha256 -mac HMAC -macopt hexkey:...`
+// invocation decryptScript runs at boot.
+func hmacTag(macKey, iv, ciphertext []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	return mac.Sum(nil)
+}
+
+// pkcs7Pad pads data to a multiple of blockSize, matching the padding
+// `openssl enc` applies by default (and removes with -d) when neither
+// side passes -nopad.
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padLen := blockSize - len(data)%blockSize
+	padding := bytes.Repeat([]byte{byte(padLen)}, padLen)
+	return append(append([]byte{}, data...), padding...)
+}
+
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("cannot unpad empty data")
+	}
+	padLen := int(data[len(data)-1])
+	if padLen == 0 || padLen > len(data) {
+		return nil, errors.New("invalid PKCS#7 padding")
+	}
+	return data[:len(data)-padLen], nil
+}
+
+// Wrapper wraps the bootstrap config's random data key with a
+// provider-specific KMS, and supplies the shell snippet that unwraps it
+// again at boot.
+type Wrapper interface {
+	// Wrap encrypts dataKey with the provider's KMS and returns the
+	// wrapped blob to embed in the ignition config.
+	Wrap(dataKey []byte) (wrapped []byte, err error)
+
+	// UnwrapScript returns the shell snippet, run by
+	// bootstrap-decrypt.service, that unwraps
+	// /opt/tectonic/bootstrap.key.wrapped into
+	// /opt/tectonic/bootstrap.key using credentials available from
+	// instance metadata.
+	UnwrapScript() string
+
+	// ServiceExtras returns extra lines to add to bootstrap-decrypt.service's
+	// [Service] section, e.g. an EnvironmentFile a passphrase-based
+	// Wrapper needs to populate its UnwrapScript's environment.
+	// Providers that unwrap entirely through instance metadata (AWS KMS,
+	// Barbican) return "".
+	ServiceExtras() string
+}
+
+// WrapperFor returns the Wrapper for encryption.Provider.
+func WrapperFor(encryption *types.Encryption) (Wrapper, error) {
+	switch encryption.Provider {
+	case types.EncryptionProviderAWSKMS:
+		return NewAWSKMSWrapper(encryption.KMSKeyARN), nil
+	case types.EncryptionProviderOpenStackBarbican:
+		return NewBarbicanWrapper(encryption.BarbicanSecretRef), nil
+	case types.EncryptionProviderLocalPassphrase:
+		return NewLocalPassphraseWrapper(encryption.Passphrase), nil
+	default:
+		return nil, errors.Errorf("unrecognized encryption provider %q", encryption.Provider)
+	}
+}
@@ -0,0 +1,29 @@
+package crypto
+
+import "fmt"
+
+// DecryptFileScript returns the shell snippet that decrypts the
+// iv||ciphertext||tag blob produced by Encrypt (found at inputPath) into
+// outputPath, verifying the trailing HMAC-SHA256 tag before trusting the
+// AES-256-CBC plaintext. encKeyHexVar and macKeyHexVar are shell
+// expressions (e.g. "$key_hex_enc") holding the two halves of the data
+// key, already hex-encoded. Every primitive used here — CBC decryption
+// and HMAC verification — has a real `openssl enc`/`openssl dgst` CLI
+// equivalent; unlike AES-GCM, which `openssl enc` can't decrypt at all.
+func DecryptFileScript(encKeyHexVar, macKeyHexVar, inputPath, outputPath string) string {
+	return fmt.Sprintf(`total=$(stat -c %%s %[1]s)
+tag_off=$((total-%[5]d))
+ct_len=$((tag_off-%[4]d))
+iv_hex=$(dd if=%[1]s bs=1 count=%[4]d 2>/dev/null | xxd -p -c %[4]d)
+tag_hex=$(dd if=%[1]s bs=1 skip=$tag_off count=%[5]d 2>/dev/null | xxd -p -c %[5]d)
+computed_hex=$(dd if=%[1]s bs=1 count=$tag_off 2>/dev/null | openssl dgst -sha256 -mac HMAC -macopt hexkey:%[3]s | awk '{print $NF}')
+if [ "$computed_hex" != "$tag_hex" ]; then
+  echo "HMAC tag mismatch decrypting %[1]s" >&2
+  exit 1
+fi
+dd if=%[1]s bs=1 skip=%[4]d count=$ct_len 2>/dev/null | openssl enc -d -aes-256-cbc -K %[2]s -iv "$iv_hex" -out %[6]s.dec
+mv %[6]s.dec %[6]s
+`,
+		inputPath, encKeyHexVar, macKeyHexVar, ivSize, macSize, outputPath,
+	)
+}
@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	pbkdf2Iterations = 200000
+	// saltSize is 8 bytes, the salt length `openssl enc -S` uses; a
+	// longer salt would be truncated there and derive a different key.
+	saltSize = 8
+)
+
+// localPassphraseWrapper wraps the data key with a key derived from a
+// user-supplied passphrase via PBKDF2, for platforms with no KMS
+// available (e.g. libvirt development clusters). The passphrase itself
+// must reach the bootstrap node out of band, since it's the only secret
+// this provider doesn't store anywhere: see ServiceExtras.
+type localPassphraseWrapper struct {
+	passphrase string
+}
+
+// NewLocalPassphraseWrapper returns a Wrapper that derives a
+// key-wrapping key from passphrase.
+func NewLocalPassphraseWrapper(passphrase string) Wrapper {
+	return &localPassphraseWrapper{passphrase: passphrase}
+}
+
+// Wrap derives a wrapping key from a fresh random salt and encrypts
+// dataKey with it. The returned blob is salt(saltSize) ||
+// Encrypt(wrappingKey, dataKey), since UnwrapScript needs the salt on
+// its own to rederive the same wrapping key with openssl's PBKDF2
+// implementation.
+func (w *localPassphraseWrapper) Wrap(dataKey []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+
+	wrapped, err := Encrypt(w.deriveWrappingKey(salt), dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap data key")
+	}
+	return append(salt, wrapped...), nil
+}
+
+// deriveWrappingKey derives the EncKeySize-byte encryption key from
+// passphrase and salt via PBKDF2, and the MacKeySize-byte MAC key the
+// same way from a second, domain-separated salt (the first saltSize
+// bytes of sha256(salt||"mac")),
+// so the two halves of the wrapping key aren't the same PBKDF2 output
+// reused for both purposes. UnwrapScript rederives both with two
+// separate `openssl enc -pbkdf2 ... -P` invocations, one per salt.
+func (w *localPassphraseWrapper) deriveWrappingKey(salt []byte) []byte {
+	encKey := pbkdf2.Key([]byte(w.passphrase), salt, pbkdf2Iterations, EncKeySize, sha256.New)
+
+	macSalt := sha256.Sum256(append(append([]byte{}, salt...), []byte("mac")...))
+	macKey := pbkdf2.Key([]byte(w.passphrase), macSalt[:saltSize], pbkdf2Iterations, MacKeySize, sha256.New)
+
+	return append(encKey, macKey...)
+}
+
+// deriveKeyScriptFmt prints the PBKDF2-derived key openssl would use for
+// AES-256-CBC with the given salt and BOOTSTRAP_PASSPHRASE, without
+// actually encrypting anything (`-P` prints the key/IV and exits). The
+// dummy -iv is required by the CLI but unused since we only read the
+// printed "key=" line.
+const deriveKeyScriptFmt = `openssl enc -aes-256-cbc -pbkdf2 -iter %d -md sha256 -S %s -pass env:BOOTSTRAP_PASSPHRASE -iv 00000000000000000000000000000000 -P | sed -n 's/^key=//p'`
+
+// UnwrapScript pulls the salt back out of bootstrap.key.wrapped,
+// rederives the encryption and MAC keys with openssl's own PBKDF2
+// implementation, strips the salt into a scratch file holding just the
+// iv||ciphertext||tag blob, and hands that to DecryptFileScript to
+// verify the HMAC tag and AES-256-CBC decrypt the wrapped data key.
+// BOOTSTRAP_PASSPHRASE is set from AFTERBURN_LIBVIRT_ATTRIBUTES_BOOTSTRAP_PASSPHRASE,
+// which ServiceExtras' EnvironmentFile supplies.
+func (w *localPassphraseWrapper) UnwrapScript() string {
+	blobPath := BootstrapKeyWrappedPath + ".blob"
+
+	script := fmt.Sprintf(`export BOOTSTRAP_PASSPHRASE="${AFTERBURN_LIBVIRT_ATTRIBUTES_BOOTSTRAP_PASSPHRASE}"
+salt_hex=$(dd if=%[1]s bs=1 count=%[2]d 2>/dev/null | xxd -p -c %[2]d)
+mac_salt_hex=$( (dd if=%[1]s bs=1 count=%[2]d 2>/dev/null; printf '%%s' mac) | openssl dgst -sha256 -binary | dd bs=1 count=%[2]d 2>/dev/null | xxd -p -c %[2]d)
+key_hex_enc=$(%[3]s)
+key_hex_mac=$(%[4]s)
+dd if=%[1]s bs=1 skip=%[2]d of=%[5]s 2>/dev/null
+`,
+		BootstrapKeyWrappedPath, saltSize,
+		fmt.Sprintf(deriveKeyScriptFmt, pbkdf2Iterations, `"$salt_hex"`),
+		fmt.Sprintf(deriveKeyScriptFmt, pbkdf2Iterations, `"$mac_salt_hex"`),
+		blobPath,
+	)
+	script += DecryptFileScript(`"$key_hex_enc"`, `"$key_hex_mac"`, blobPath, BootstrapKeyPath)
+	script += fmt.Sprintf("rm -f %s\n", blobPath)
+	return script
+}
+
+// ServiceExtras loads afterburn's metadata file, which on libvirt
+// populates AFTERBURN_LIBVIRT_ATTRIBUTES_* from the domain's SMBIOS OEM
+// strings — the out-of-band channel this passphrase travels through,
+// since the installer never writes it into the ignition config itself.
+// UnwrapScript copies the passphrase from there into BOOTSTRAP_PASSPHRASE,
+// since systemd does not expand variables in Environment= lines.
+func (w *localPassphraseWrapper) ServiceExtras() string {
+	return `EnvironmentFile=-/run/metadata/afterburn
+`
+}
@@ -0,0 +1,128 @@
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/ignition/config/util"
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/pkg/errors"
+	"github.com/vincent-petithory/dataurl"
+
+	"github.com/openshift/installer/pkg/asset/ignition/bootstrap/crypto"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const bootstrapDecryptScript = "/opt/tectonic/bootstrap-decrypt.sh"
+
+// encryptSensitiveFiles encrypts the files in
+// a.sensitivePaths (TLS keys, kubeconfigs, and the other secrets staged
+// by addBootstrapFiles/addBootkubeFiles/addTLSCertFiles) in place with a
+// fresh random data key, wraps that data key with the configured KMS
+// provider, and appends a bootstrap-decrypt.service unit that unwraps
+// the key and decrypts those files again before bootkube.service and
+// kubelet.service start. Everything else in a.Config.Storage.Files
+// (bootkube.sh, operator manifests, report-progress.sh, ...) isn't
+// secret and is left untouched.
+func (a *Bootstrap) encryptSensitiveFiles(encryption *types.Encryption) error {
+	dataKey, err := crypto.GenerateDataKey()
+	if err != nil {
+		return err
+	}
+
+	wrapper, err := crypto.WrapperFor(encryption)
+	if err != nil {
+		return errors.Wrap(err, "failed to select KMS wrapper")
+	}
+	wrappedKey, err := wrapper.Wrap(dataKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to wrap data key")
+	}
+
+	sensitive := make(map[string]bool, len(a.sensitivePaths))
+	for _, p := range a.sensitivePaths {
+		sensitive[p] = true
+	}
+
+	encryptedPaths := make([]string, 0, len(a.sensitivePaths))
+	for i, f := range a.Config.Storage.Files {
+		if !sensitive[f.Path] {
+			continue
+		}
+		plaintext, err := decodeDataURL(f.Contents.Source)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %s for encryption", f.Path)
+		}
+		ciphertext, err := crypto.Encrypt(dataKey, plaintext)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt %s", f.Path)
+		}
+		a.Config.Storage.Files[i].Contents.Source = dataurl.EncodeBytes(ciphertext)
+		encryptedPaths = append(encryptedPaths, f.Path)
+	}
+
+	a.Config.Storage.Files = append(
+		a.Config.Storage.Files,
+		igntypes.File{
+			Node: igntypes.Node{Path: crypto.BootstrapKeyWrappedPath},
+			FileEmbedded1: igntypes.FileEmbedded1{
+				Contents: igntypes.FileContents{Source: dataurl.EncodeBytes(wrappedKey)},
+				Mode:     util.IntToPtr(0600),
+			},
+		},
+		igntypes.File{
+			Node: igntypes.Node{Path: bootstrapDecryptScript},
+			FileEmbedded1: igntypes.FileEmbedded1{
+				Contents: igntypes.FileContents{Source: dataurl.EncodeBytes([]byte(decryptScript(wrapper, encryptedPaths)))},
+				Mode:     util.IntToPtr(0555),
+			},
+		},
+	)
+
+	a.Config.Systemd.Units = append(a.Config.Systemd.Units, igntypes.Unit{
+		Name:     "bootstrap-decrypt.service",
+		Enabled:  util.BoolToPtr(true),
+		Contents: bootstrapDecryptUnitContents(wrapper),
+	})
+
+	return nil
+}
+
+// bootstrapDecryptUnitContents builds bootstrap-decrypt.service, adding
+// wrapper.ServiceExtras() to its [Service] section for wrappers (like
+// the local-passphrase one) whose UnwrapScript needs environment that
+// isn't available by default.
+func bootstrapDecryptUnitContents(wrapper crypto.Wrapper) string {
+	return `[Unit]
+Description=Decrypt bootstrap secrets
+Before=bootkube.service kubelet.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+` + wrapper.ServiceExtras() + `ExecStart=` + bootstrapDecryptScript + `
+
+[Install]
+WantedBy=multi-user.target
+`
+}
+
+// decryptScript builds the shell script that unwraps the data key via
+// wrapper into crypto.BootstrapKeyPath, splits it into its AES and HMAC halves
+// with head/tail (crypto.GenerateDataKey's format is simply the two
+// concatenated, so no HKDF is needed on the node), and uses
+// crypto.DecryptFileScript to decrypt each of paths in place.
+func decryptScript(wrapper crypto.Wrapper, paths []string) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\nset -euo pipefail\n\n")
+	b.WriteString(wrapper.UnwrapScript())
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "key_hex_enc=$(head -c %d %s | xxd -p -c 256)\n", crypto.EncKeySize, crypto.BootstrapKeyPath)
+	fmt.Fprintf(&b, "key_hex_mac=$(tail -c %d %s | xxd -p -c 256)\n\n", crypto.MacKeySize, crypto.BootstrapKeyPath)
+	for _, p := range paths {
+		b.WriteString(crypto.DecryptFileScript(`"$key_hex_enc"`, `"$key_hex_mac"`, p, p))
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "shred -u %s\n", crypto.BootstrapKeyPath)
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// mergeIgnitionOverrides deep-merges a machine pool's user-supplied
+// IgnitionOverrides into cfg.
+//
+// Files are added verbatim; a path that collides with one the installer
+// already generated is an error, so the conflict is surfaced to the user
+// rather than silently dropped or silently overwritten. Systemd units
+// are merged per-unit: a unit whose name doesn't match an existing one
+// is appended, while a name collision is handled according to the
+// override's DropinMerge strategy: Replace overwrites the existing
+// unit's Contents outright, while Append (the default) adds Contents as
+// a new systemd drop-in alongside the installer-generated unit, rather
+// than concatenating raw text into its Contents. Users are always
+// appended.
+func mergeIgnitionOverrides(cfg *igntypes.Config, overrides *types.IgnitionOverrides) error {
+	if overrides == nil {
+		return nil
+	}
+
+	existingFiles := make(map[string]bool, len(cfg.Storage.Files))
+	for _, f := range cfg.Storage.Files {
+		existingFiles[f.Path] = true
+	}
+	for _, f := range overrides.Files {
+		if existingFiles[f.Path] {
+			return errors.Errorf("ignitionOverrides: file %q conflicts with a file the installer already generates", f.Path)
+		}
+		mode := f.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		cfg.Storage.Files = append(cfg.Storage.Files, ignition.FileFromString(f.Path, mode, f.Contents))
+		existingFiles[f.Path] = true
+	}
+
+	unitIndex := make(map[string]int, len(cfg.Systemd.Units))
+	for i, u := range cfg.Systemd.Units {
+		unitIndex[u.Name] = i
+	}
+	for _, u := range overrides.Units {
+		if i, ok := unitIndex[u.Name]; ok {
+			if u.DropinMerge == types.IgnitionDropinMergeReplace {
+				cfg.Systemd.Units[i].Contents = u.Contents
+			} else {
+				cfg.Systemd.Units[i].Dropins = append(cfg.Systemd.Units[i].Dropins, igntypes.SystemdDropin{
+					Name:     fmt.Sprintf("%02d-ignition-overrides.conf", len(cfg.Systemd.Units[i].Dropins)+1),
+					Contents: u.Contents,
+				})
+			}
+			if u.Enabled != nil {
+				cfg.Systemd.Units[i].Enabled = u.Enabled
+			}
+			continue
+		}
+		cfg.Systemd.Units = append(cfg.Systemd.Units, igntypes.Unit{
+			Name:     u.Name,
+			Contents: u.Contents,
+			Enabled:  u.Enabled,
+		})
+		unitIndex[u.Name] = len(cfg.Systemd.Units) - 1
+	}
+
+	for _, u := range overrides.Users {
+		keys := make([]igntypes.SSHAuthorizedKey, len(u.SSHAuthorizedKeys))
+		for i, k := range u.SSHAuthorizedKeys {
+			keys[i] = igntypes.SSHAuthorizedKey(k)
+		}
+		cfg.Passwd.Users = append(cfg.Passwd.Users, igntypes.PasswdUser{
+			Name:              u.Name,
+			SSHAuthorizedKeys: keys,
+		})
+	}
+
+	return nil
+}
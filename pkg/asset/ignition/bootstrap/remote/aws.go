@@ -0,0 +1,53 @@
+package remote
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+// awsUploader uploads the bootstrap payload to S3 and returns a
+// presigned GetObject URL.
+type awsUploader struct {
+	region  string
+	hosting *types.RemoteHosting
+}
+
+// NewAWSUploader returns an Uploader that stores the bootstrap payload
+// in the bucket named by hosting.Bucket, in the cluster's AWS region.
+func NewAWSUploader(platform *awstypes.Platform, hosting *types.RemoteHosting) Uploader {
+	return &awsUploader{region: platform.Region, hosting: hosting}
+}
+
+func (u *awsUploader) Upload(name string, data []byte, ttl time.Duration) (string, error) {
+	sess, err := session.NewSession(aws.NewConfig().WithRegion(u.region))
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	client := s3.New(sess)
+
+	key := fmt.Sprintf("bootstrap/%s", name)
+	if _, err := client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(u.hosting.Bucket),
+		Key:    aws.String(key),
+		Body:   bytesReader(data),
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload %s to s3://%s: %w", key, u.hosting.Bucket, err)
+	}
+
+	req, _ := client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(u.hosting.Bucket),
+		Key:    aws.String(key),
+	})
+	url, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign s3://%s/%s: %w", u.hosting.Bucket, key, err)
+	}
+	return url, nil
+}
@@ -0,0 +1,117 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/types"
+	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
+)
+
+// libvirtUploader serves the bootstrap payload from a local,
+// installer-managed HTTP listener reachable by the libvirt guest
+// network, since libvirt installs have no remote object store of their
+// own. Unlike the AWS and OpenStack uploaders, it has no
+// platform-specific settings to read (the listen/advertise address comes
+// entirely from hosting.Endpoint); platform is accepted only to keep the
+// constructor's signature consistent with NewAWSUploader/
+// NewOpenStackUploader and UploaderFor's dispatch.
+//
+// The server is plain HTTP, not HTTPS: it has no certificate the
+// bootstrap guest's embedded root CA would validate, since that CA is
+// the cluster's own and this listener predates the cluster entirely.
+// That's fine for integrity (the pointer ignition's Verification.Hash
+// already covers that regardless of transport); it does mean the
+// payload is readable by anything that can see the libvirt bridge
+// network, same as the old inline-everything bootstrap.ign was.
+//
+// The listener's lifetime is tied to this process: it must stay running
+// until the bootstrap node has fetched the payload, which in practice
+// means keeping `openshift-install create cluster` running until
+// bootstrap completes, same as it already waits for the cluster's other
+// bootstrap signals. There's no separate daemon to hand it off to.
+type libvirtUploader struct {
+	hosting *types.RemoteHosting
+	dir     string
+}
+
+// NewLibvirtUploader returns an Uploader that serves the bootstrap
+// payload over HTTP from a directory on the libvirt host, addressed via
+// hosting.Endpoint (the host-side address reachable from the guest
+// network, e.g. the libvirt bridge's gateway IP).
+func NewLibvirtUploader(platform *libvirttypes.Platform, hosting *types.RemoteHosting) Uploader {
+	return &libvirtUploader{hosting: hosting}
+}
+
+func (u *libvirtUploader) Upload(name string, data []byte, ttl time.Duration) (string, error) {
+	if u.dir == "" {
+		dir, err := ioutil.TempDir("", "bootstrap-remote")
+		if err != nil {
+			return "", fmt.Errorf("failed to create local bootstrap hosting dir: %w", err)
+		}
+		u.dir = dir
+	}
+
+	path := filepath.Join(u.dir, name)
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if u.hosting.Endpoint == "" {
+		return "", fmt.Errorf("remoteHosting.endpoint is required on libvirt (the host address reachable from the guest network)")
+	}
+
+	if err := u.serve(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s", u.hosting.Endpoint, name), nil
+}
+
+// serve starts (once) a long-lived HTTP server rooted at u.dir so that
+// the bootstrap guest can fetch the uploaded payload during boot.
+func (u *libvirtUploader) serve() error {
+	addr, err := endpointAddr(u.hosting.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to parse remoteHosting.endpoint %q: %w", u.hosting.Endpoint, err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		if isAddrInUse(err) {
+			// Another upload already started the listener for this install.
+			return nil
+		}
+		return fmt.Errorf("failed to listen on %s for local bootstrap hosting: %w", addr, err)
+	}
+
+	go func() {
+		if err := http.Serve(listener, http.FileServer(http.Dir(u.dir))); err != nil {
+			log.WithError(err).Error("local bootstrap hosting server stopped; the bootstrap node may fail to fetch its boot configuration if it hasn't already")
+		}
+	}()
+	return nil
+}
+
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
+
+// endpointAddr extracts the host:port to listen on from an endpoint URL
+// such as "http://192.168.126.1:8080".
+func endpointAddr(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	return u.Host, nil
+}
@@ -0,0 +1,33 @@
+package remote
+
+import (
+	"time"
+
+	clusteropenstack "github.com/openshift/installer/pkg/asset/cluster/openstack"
+	"github.com/openshift/installer/pkg/types"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+// openStackUploader uploads the bootstrap payload to Swift and returns a
+// TempURL, delegating the OpenStack SDK glue to
+// pkg/asset/cluster/openstack so the Glance image upload and the
+// bootstrap TempURL upload share one authentication and TempURL-key
+// path. The account's existing TempURL key is reused; keys are never
+// rotated.
+type openStackUploader struct {
+	platform *openstacktypes.Platform
+}
+
+// NewOpenStackUploader returns an Uploader that stores the bootstrap
+// payload in platform.SwiftContainer, falling back to hosting.Bucket if
+// platform.SwiftContainer is unset.
+func NewOpenStackUploader(platform *openstacktypes.Platform, hosting *types.RemoteHosting) Uploader {
+	if platform.SwiftContainer == "" {
+		platform.SwiftContainer = hosting.Bucket
+	}
+	return &openStackUploader{platform: platform}
+}
+
+func (u *openStackUploader) Upload(name string, data []byte, ttl time.Duration) (string, error) {
+	return clusteropenstack.UploadBootstrapIgnition(u.platform, data, ttl)
+}
@@ -0,0 +1,51 @@
+// Package remote uploads the rendered bootstrap boot configuration to a
+// platform-specific object store so that the bootstrap node can fetch it
+// through a small HTTPS pointer config instead of carrying it in full as
+// instance user-data.
+package remote
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Uploader stores the bootstrap payload in a platform-specific object
+// store and returns a URL the bootstrap node can fetch it from: HTTPS
+// for the AWS and OpenStack stores, plain HTTP for libvirt's local
+// server.
+type Uploader interface {
+	// Upload stores data under name and returns the URL it can be
+	// fetched from. The URL (and the object itself, where the store
+	// supports expiry) must remain valid for at least ttl.
+	Upload(name string, data []byte, ttl time.Duration) (url string, err error)
+}
+
+// UploaderFor returns the Uploader for the given platform, using the
+// RemoteHosting configuration to address the target store.
+func UploaderFor(platform *types.Platform, hosting *types.RemoteHosting) (Uploader, error) {
+	switch platform.Name() {
+	case types.PlatformNameAWS:
+		return NewAWSUploader(platform.AWS, hosting), nil
+	case types.PlatformNameOpenstack:
+		return NewOpenStackUploader(platform.OpenStack, hosting), nil
+	case types.PlatformNameLibvirt:
+		return NewLibvirtUploader(platform.Libvirt, hosting), nil
+	default:
+		return nil, unsupportedPlatformError(platform.Name())
+	}
+}
+
+// bytesReader adapts a []byte to the io.ReadSeeker the storage SDKs
+// expect for upload bodies.
+func bytesReader(data []byte) io.ReadSeeker {
+	return bytes.NewReader(data)
+}
+
+type unsupportedPlatformError string
+
+func (e unsupportedPlatformError) Error() string {
+	return "remote bootstrap hosting is not supported on platform " + string(e)
+}
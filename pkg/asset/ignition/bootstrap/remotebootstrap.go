@@ -0,0 +1,150 @@
+package bootstrap
+
+import (
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/coreos/ignition/config/util"
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition/bootstrap/remote"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	remoteBootstrapIgnFilename = "bootstrap-pointer.ign"
+	defaultRemoteHostingTTL    = 24 * time.Hour
+)
+
+// RemoteBootstrap is an asset that uploads the full Bootstrap ignition
+// config to an object store and generates a small pointer ignition
+// config that fetches it and verifies it against a sha512 hash. It is
+// only populated when InstallConfig.Bootstrap.RemoteHosting is set, or
+// on OpenStack when Platform.OpenStack.SwiftContainer is set (see
+// remoteHostingFor); otherwise Bootstrap's output is shipped directly.
+type RemoteBootstrap struct {
+	File *asset.File
+}
+
+var _ asset.WritableAsset = (*RemoteBootstrap)(nil)
+
+// Dependencies returns the assets on which the RemoteBootstrap asset
+// depends.
+func (a *RemoteBootstrap) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&Bootstrap{},
+	}
+}
+
+// Generate uploads the Bootstrap asset's rendered output and generates
+// the pointer ignition config. It is a no-op, producing no file, when
+// remoteHostingFor returns nil.
+func (a *RemoteBootstrap) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	bootstrap := &Bootstrap{}
+	dependencies.Get(installConfig, bootstrap)
+
+	hosting := remoteHostingFor(installConfig.Config)
+	if hosting == nil {
+		return nil
+	}
+
+	ttl := hosting.TTL.Duration
+	if ttl == 0 {
+		ttl = defaultRemoteHostingTTL
+	}
+
+	uploader, err := remote.UploaderFor(&installConfig.Config.Platform, hosting)
+	if err != nil {
+		return errors.Wrap(err, "failed to create bootstrap remote-hosting uploader")
+	}
+
+	url, err := uploader.Upload(bootstrapIgnFilename, bootstrap.File.Data, ttl)
+	if err != nil {
+		return errors.Wrap(err, "failed to upload bootstrap config")
+	}
+
+	sum := sha512.Sum512(bootstrap.File.Data)
+	hash := fmt.Sprintf("sha512-%x", sum)
+
+	// The uploaded config is fetched from the object store's own public
+	// endpoint (S3, Swift) or over plain HTTP (libvirt's ephemeral
+	// server, see remote/libvirt.go) — none of those are validated by
+	// the cluster's own root CA, so there's no CertificateAuthorities
+	// entry to add here; the sha512 Verification hash above is what
+	// actually protects the fetch's integrity.
+	pointer := &igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+			Config: igntypes.IgnitionConfig{
+				Append: []igntypes.ConfigReference{
+					{
+						Source:       url,
+						Verification: igntypes.Verification{Hash: util.StrToPtr(hash)},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(pointer)
+	if err != nil {
+		return errors.Wrap(err, "failed to Marshal bootstrap pointer Ignition config")
+	}
+
+	a.File = &asset.File{
+		Filename: remoteBootstrapIgnFilename,
+		Data:     data,
+	}
+	return nil
+}
+
+// remoteHostingFor returns the RemoteHosting configuration to use, or
+// nil if the bootstrap config should be shipped in full. OpenStack
+// installs default to remote hosting through Swift whenever
+// Platform.OpenStack.SwiftContainer is set, even without an explicit
+// Bootstrap.RemoteHosting, since Swift TempURLs are already how
+// OpenStack installs avoid the user-data size limit.
+func remoteHostingFor(installConfig *types.InstallConfig) *types.RemoteHosting {
+	if installConfig.Bootstrap != nil && installConfig.Bootstrap.RemoteHosting != nil {
+		return installConfig.Bootstrap.RemoteHosting
+	}
+	if installConfig.Platform.OpenStack != nil && installConfig.Platform.OpenStack.SwiftContainer != "" {
+		return &types.RemoteHosting{Bucket: installConfig.Platform.OpenStack.SwiftContainer}
+	}
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *RemoteBootstrap) Name() string {
+	return "Bootstrap Pointer Ignition Config"
+}
+
+// Files returns the files generated by the asset.
+func (a *RemoteBootstrap) Files() []*asset.File {
+	if a.File != nil {
+		return []*asset.File{a.File}
+	}
+	return []*asset.File{}
+}
+
+// Load returns the bootstrap pointer ignition from disk.
+func (a *RemoteBootstrap) Load(f asset.FileFetcher) (found bool, err error) {
+	file, err := f.FetchByName(remoteBootstrapIgnFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	a.File = file
+	return true, nil
+}
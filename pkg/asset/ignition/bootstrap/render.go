@@ -0,0 +1,34 @@
+package bootstrap
+
+import (
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// BootstrapRenderer converts the bootstrap content collected by
+// Bootstrap.Generate (files, systemd units and passwd users, held in the
+// canonical Ignition v2.2 representation) into the wire format consumed
+// by the target platform.
+type BootstrapRenderer interface {
+	// Render serializes the bootstrap content held in cfg into the
+	// renderer's target format.
+	Render(cfg *igntypes.Config) ([]byte, error)
+}
+
+// rendererForFormat returns the BootstrapRenderer for the given
+// BootstrapFormat, defaulting to the Ignition v2.2 renderer when format
+// is empty.
+func rendererForFormat(format types.BootstrapFormat) (BootstrapRenderer, error) {
+	switch format {
+	case "", types.BootstrapFormatIgnitionV2:
+		return &ignitionV2Renderer{}, nil
+	case types.BootstrapFormatIgnitionV3:
+		return &ignitionV3Renderer{}, nil
+	case types.BootstrapFormatCloudConfig:
+		return &cloudInitRenderer{}, nil
+	default:
+		return nil, errors.Errorf("unrecognized bootstrap format %q", format)
+	}
+}
@@ -0,0 +1,115 @@
+package bootstrap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/pkg/errors"
+	"github.com/vincent-petithory/dataurl"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const cloudConfigHeader = "#cloud-config\n"
+
+// cloudInitRenderer renders the bootstrap content as a cloud-init
+// cloud-config user-data document, for non-CoreOS distros and
+// cluster-api providers that don't consume Ignition.
+type cloudInitRenderer struct{}
+
+var _ BootstrapRenderer = (*cloudInitRenderer)(nil)
+
+// cloudConfig is the subset of the cloud-init cloud-config schema that
+// the bootstrap content maps onto.
+type cloudConfig struct {
+	WriteFiles []cloudConfigFile `yaml:"write_files"`
+	RunCmd     []string          `yaml:"runcmd"`
+	Users      []cloudConfigUser `yaml:"users"`
+}
+
+type cloudConfigFile struct {
+	Path        string `yaml:"path"`
+	Encoding    string `yaml:"encoding"`
+	Content     string `yaml:"content"`
+	Permissions string `yaml:"permissions"`
+}
+
+type cloudConfigUser struct {
+	Name              string   `yaml:"name"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys"`
+}
+
+// Render decodes the files embedded in cfg's Ignition data URLs, lays
+// the systemd units down under /etc/systemd/system (and their drop-ins
+// under /etc/systemd/system/<unit>.d) via write_files, and
+// enables/starts them with runcmd.
+func (r *cloudInitRenderer) Render(cfg *igntypes.Config) ([]byte, error) {
+	out := cloudConfig{}
+
+	for _, f := range cfg.Storage.Files {
+		content, err := decodeDataURL(f.Contents.Source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode contents of %s", f.Path)
+		}
+		mode := 0644
+		if f.Mode != nil {
+			mode = *f.Mode
+		}
+		out.WriteFiles = append(out.WriteFiles, cloudConfigFile{
+			Path:        f.Path,
+			Encoding:    "b64",
+			Content:     base64.StdEncoding.EncodeToString(content),
+			Permissions: fmt.Sprintf("%04o", mode),
+		})
+	}
+
+	for _, u := range cfg.Systemd.Units {
+		if u.Contents != "" {
+			out.WriteFiles = append(out.WriteFiles, cloudConfigFile{
+				Path:        filepath.Join("/etc/systemd/system", u.Name),
+				Encoding:    "b64",
+				Content:     base64.StdEncoding.EncodeToString([]byte(u.Contents)),
+				Permissions: "0644",
+			})
+		}
+		for _, d := range u.Dropins {
+			out.WriteFiles = append(out.WriteFiles, cloudConfigFile{
+				Path:        filepath.Join("/etc/systemd/system", u.Name+".d", d.Name),
+				Encoding:    "b64",
+				Content:     base64.StdEncoding.EncodeToString([]byte(d.Contents)),
+				Permissions: "0644",
+			})
+		}
+		if u.Enabled != nil && *u.Enabled {
+			out.RunCmd = append(out.RunCmd, fmt.Sprintf("systemctl enable --now %s", u.Name))
+		}
+	}
+
+	for _, p := range cfg.Passwd.Users {
+		var keys []string
+		for _, k := range p.SSHAuthorizedKeys {
+			keys = append(keys, string(k))
+		}
+		out.Users = append(out.Users, cloudConfigUser{
+			Name:              p.Name,
+			SSHAuthorizedKeys: keys,
+		})
+	}
+
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to Marshal cloud-config")
+	}
+	return append([]byte(cloudConfigHeader), data...), nil
+}
+
+// decodeDataURL decodes the data: URL produced by pkg/asset/ignition's
+// file helpers back into its raw contents.
+func decodeDataURL(source string) ([]byte, error) {
+	u, err := dataurl.DecodeString(source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode data URL")
+	}
+	return u.Data, nil
+}
@@ -0,0 +1,24 @@
+package bootstrap
+
+import (
+	"encoding/json"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/pkg/errors"
+)
+
+// ignitionV2Renderer renders the bootstrap content as an Ignition v2.2
+// config, the installer's original and default bootstrap format.
+type ignitionV2Renderer struct{}
+
+var _ BootstrapRenderer = (*ignitionV2Renderer)(nil)
+
+// Render marshals cfg directly, since cfg is already an Ignition v2.2
+// config.
+func (r *ignitionV2Renderer) Render(cfg *igntypes.Config) ([]byte, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to Marshal Ignition v2.2 config")
+	}
+	return data, nil
+}
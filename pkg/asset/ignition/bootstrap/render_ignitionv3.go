@@ -0,0 +1,75 @@
+package bootstrap
+
+import (
+	"encoding/json"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	ign3types "github.com/coreos/ignition/v2/config/v3_1/types"
+	ign3util "github.com/coreos/ignition/v2/config/util"
+	"github.com/pkg/errors"
+)
+
+// ignitionV3Renderer renders the bootstrap content as an Ignition v3.x
+// config, for platforms whose boot agent has moved past the v2.x schema.
+type ignitionV3Renderer struct{}
+
+var _ BootstrapRenderer = (*ignitionV3Renderer)(nil)
+
+// Render re-shapes cfg, an Ignition v2.2 config, into the Ignition v3.1
+// schema and marshals it.
+func (r *ignitionV3Renderer) Render(cfg *igntypes.Config) ([]byte, error) {
+	out := ign3types.Config{
+		Ignition: ign3types.Ignition{
+			Version: ign3types.MaxVersion.String(),
+		},
+	}
+
+	for _, f := range cfg.Storage.Files {
+		source := f.Contents.Source
+		out.Storage.Files = append(out.Storage.Files, ign3types.File{
+			Node: ign3types.Node{
+				Path:      f.Path,
+				Overwrite: ign3util.BoolToPtr(true),
+			},
+			FileEmbedded1: ign3types.FileEmbedded1{
+				Contents: ign3types.Resource{
+					Source: ign3util.StrToPtr(source),
+				},
+				Mode: f.Mode,
+			},
+		})
+	}
+
+	for _, u := range cfg.Systemd.Units {
+		var dropins []ign3types.Dropin
+		for _, d := range u.Dropins {
+			dropins = append(dropins, ign3types.Dropin{
+				Name:     d.Name,
+				Contents: ign3util.StrToPtr(d.Contents),
+			})
+		}
+		out.Systemd.Units = append(out.Systemd.Units, ign3types.Unit{
+			Name:     u.Name,
+			Contents: ign3util.StrToPtr(u.Contents),
+			Enabled:  u.Enabled,
+			Dropins:  dropins,
+		})
+	}
+
+	for _, p := range cfg.Passwd.Users {
+		var keys []ign3types.SSHAuthorizedKey
+		for _, k := range p.SSHAuthorizedKeys {
+			keys = append(keys, ign3types.SSHAuthorizedKey(k))
+		}
+		out.Passwd.Users = append(out.Passwd.Users, ign3types.PasswdUser{
+			Name:              p.Name,
+			SSHAuthorizedKeys: keys,
+		})
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to Marshal Ignition v3.1 config")
+	}
+	return data, nil
+}
@@ -0,0 +1,76 @@
+package azure
+
+import "github.com/pkg/errors"
+
+// Platform stores all the global configuration that all machinesets
+// use when installing on Azure.
+type Platform struct {
+	// Region is the Azure region that the cluster will be installed into.
+	Region string `json:"region"`
+
+	// ResourceGroupName is the name of the resource group that holds (or
+	// will be created to hold) the cluster's Azure resources. It may be
+	// left empty; no name is generated for it when it is.
+	// +optional
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// TenantID is the ID of the Azure Active Directory tenant that owns
+	// the subscription used to install the cluster.
+	TenantID string `json:"tenantID"`
+
+	// SubscriptionID is the ID of the Azure subscription that the
+	// cluster's resources will be billed to.
+	SubscriptionID string `json:"subscriptionID"`
+
+	// Image is the VM image used to boot the master and worker machines.
+	Image VMImage `json:"image"`
+}
+
+// VMImage is a reference to the virtual machine image used to create
+// master and worker machines, mirroring the publisher/offer/sku/version
+// tuple used by the Azure image_reference block.
+type VMImage struct {
+	// Publisher is the publisher of the image.
+	Publisher string `json:"publisher"`
+
+	// Offer is the name of the image offer.
+	Offer string `json:"offer"`
+
+	// SKU is the image SKU.
+	SKU string `json:"sku"`
+
+	// Version is the image version.
+	Version string `json:"version"`
+}
+
+// Validate checks that the fields needed to authenticate against Azure
+// and locate the master/worker VM image are all present. ResourceGroupName
+// is optional and is not checked.
+func (p *Platform) Validate() error {
+	if p.Region == "" {
+		return errors.New("region is required")
+	}
+	if p.TenantID == "" {
+		return errors.New("tenantID is required")
+	}
+	if p.SubscriptionID == "" {
+		return errors.New("subscriptionID is required")
+	}
+	return p.Image.validate()
+}
+
+// validate checks that image is fully specified; a partial
+// publisher/offer/sku/version tuple can't be resolved to a single image.
+func (i *VMImage) validate() error {
+	switch {
+	case i.Publisher == "":
+		return errors.New("image.publisher is required")
+	case i.Offer == "":
+		return errors.New("image.offer is required")
+	case i.SKU == "":
+		return errors.New("image.sku is required")
+	case i.Version == "":
+		return errors.New("image.version is required")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	"github.com/openshift/installer/pkg/types/openstack"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,6 +13,8 @@ import (
 const (
 	// PlatformNameAWS is name for AWS platform.
 	PlatformNameAWS string = "aws"
+	// PlatformNameAzure is name for Azure platform.
+	PlatformNameAzure string = "azure"
 	// PlatformNameOpenstack is name for Openstack platform.
 	PlatformNameOpenstack string = "openstack"
 	// PlatformNameLibvirt is name for Libvirt platform.
@@ -46,6 +49,114 @@ type InstallConfig struct {
 
 	// PullSecret is the secret to use when pulling images.
 	PullSecret string `json:"pullSecret"`
+
+	// Bootstrap is the configuration for the bootstrap node's generated
+	// boot configuration.
+	// +optional
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+
+	// Encryption configures at-rest encryption, via a KMS-wrapped data
+	// key, of the TLS keys, kubeconfigs and pull secret embedded in the
+	// generated bootstrap boot configuration.
+	// +optional
+	Encryption *Encryption `json:"encryption,omitempty"`
+}
+
+// Encryption is the configuration for encrypting the secrets embedded in
+// the generated bootstrap boot configuration at rest.
+type Encryption struct {
+	// Provider selects the KMS used to wrap the random data key that
+	// encrypts the bootstrap config's sensitive files.
+	Provider EncryptionProvider `json:"provider"`
+
+	// KMSKeyARN is the ARN of the AWS KMS key used to wrap the data key.
+	// Only used when Provider is EncryptionProviderAWSKMS.
+	// +optional
+	KMSKeyARN string `json:"kmsKeyARN,omitempty"`
+
+	// BarbicanSecretRef is the name given to the new OpenStack Barbican
+	// secret that the data key is stored in. Only used when Provider is
+	// EncryptionProviderOpenStackBarbican.
+	// +optional
+	BarbicanSecretRef string `json:"barbicanSecretRef,omitempty"`
+
+	// Passphrase derives the key-wrapping key when Provider is
+	// EncryptionProviderLocalPassphrase. The installer reads it only from
+	// this field, and does not write it into the generated bootstrap boot
+	// configuration.
+	// +optional
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// EncryptionProvider is the KMS used to wrap the bootstrap config's data
+// key.
+type EncryptionProvider string
+
+const (
+	// EncryptionProviderAWSKMS wraps the data key with AWS KMS.
+	EncryptionProviderAWSKMS EncryptionProvider = "aws-kms"
+	// EncryptionProviderOpenStackBarbican wraps the data key with an
+	// OpenStack Barbican secret.
+	EncryptionProviderOpenStackBarbican EncryptionProvider = "openstack-barbican"
+	// EncryptionProviderLocalPassphrase wraps the data key with a
+	// passphrase-derived key, for platforms with no KMS available (e.g.
+	// libvirt development clusters).
+	EncryptionProviderLocalPassphrase EncryptionProvider = "local-passphrase"
+)
+
+// Bootstrap is the configuration for the bootstrap node's generated boot
+// configuration.
+type Bootstrap struct {
+	// Format selects the wire format that the bootstrap node's boot
+	// configuration is rendered into. Defaults to BootstrapFormatIgnitionV2
+	// when unset.
+	// +optional
+	Format BootstrapFormat `json:"format,omitempty"`
+
+	// RemoteHosting, if set, causes the generated boot configuration to
+	// be uploaded to an object store rather than shipped in full as
+	// instance user-data. The bootstrap node instead boots from a small
+	// pointer config that fetches and verifies the full config over
+	// HTTPS (plain HTTP on libvirt), keeping the user-data under platform
+	// size limits (e.g. AWS's 16 KiB).
+	// +optional
+	RemoteHosting *RemoteHosting `json:"remoteHosting,omitempty"`
+}
+
+// BootstrapFormat is the wire format used for the bootstrap node's boot
+// configuration.
+type BootstrapFormat string
+
+const (
+	// BootstrapFormatIgnitionV2 renders the bootstrap config as an
+	// Ignition v2.2 config. This is the default.
+	BootstrapFormatIgnitionV2 BootstrapFormat = "ignition-v2"
+	// BootstrapFormatIgnitionV3 renders the bootstrap config as an
+	// Ignition v3.x config.
+	BootstrapFormatIgnitionV3 BootstrapFormat = "ignition-v3"
+	// BootstrapFormatCloudConfig renders the bootstrap config as a
+	// cloud-init cloud-config user-data document.
+	BootstrapFormatCloudConfig BootstrapFormat = "cloud-config"
+)
+
+// RemoteHosting configures where the full bootstrap boot configuration is
+// uploaded so that a small HTTPS pointer config can be served to the
+// bootstrap node in its place.
+type RemoteHosting struct {
+	// Bucket is the name of the object store bucket (or Swift container)
+	// that the full bootstrap config is uploaded to.
+	Bucket string `json:"bucket"`
+
+	// Endpoint is the base URL of the object store. It is optional for
+	// stores that are addressed by bucket name alone (e.g. AWS S3), and
+	// required for installer-managed or self-hosted stores.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// TTL is how long the uploaded bootstrap config, and any pre-signed
+	// download URL for it, remains valid.
+	// +optional
+	TTL metav1.Duration `json:"ttl,omitempty"`
 }
 
 // MasterCount returns the number of replicas in the master machine pool,
@@ -75,6 +186,9 @@ type Platform struct {
 	// AWS is the configuration used when installing on AWS.
 	AWS *aws.Platform `json:"aws,omitempty"`
 
+	// Azure is the configuration used when installing on Azure.
+	Azure *azure.Platform `json:"azure,omitempty"`
+
 	// Libvirt is the configuration used when installing on libvirt.
 	Libvirt *libvirt.Platform `json:"libvirt,omitempty"`
 
@@ -92,6 +206,9 @@ func (p *Platform) Name() string {
 	if p.AWS != nil {
 		return PlatformNameAWS
 	}
+	if p.Azure != nil {
+		return PlatformNameAzure
+	}
 	if p.Libvirt != nil {
 		return PlatformNameLibvirt
 	}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MachinePool is a pool of machines to be installed.
+type MachinePool struct {
+	// Name is the name of the machine pool, e.g. "master" or "worker".
+	Name string `json:"name"`
+
+	// Replicas is the count of machines for this machine pool.
+	// Default is 1.
+	// +optional
+	Replicas *int64 `json:"replicas,omitempty"`
+
+	// Labels is a map of Kubernetes node labels to apply to each machine
+	// in this pool. Only the pool named "bootstrap" is read; labels on
+	// the master and worker pools are not applied.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are Kubernetes node taints to apply to each machine in this
+	// pool. Only the pool named "bootstrap" is read; taints on the master
+	// and worker pools are not applied.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// IgnitionOverrides contains extra Ignition fragments to merge into
+	// the generated config for this pool's role, so users can, for
+	// example, add custom kubelet flags, drop-in systemd units, or ship
+	// organization CA bundles without forking the installer. Only the
+	// bootstrap Ignition config is generated here, so only the overrides
+	// of the pool named "bootstrap" are merged.
+	// +optional
+	IgnitionOverrides *IgnitionOverrides `json:"ignitionOverrides,omitempty"`
+}
+
+// IgnitionOverrides contains extra Ignition fragments supplied by the
+// user to be merged into a role's generated Ignition config.
+type IgnitionOverrides struct {
+	// Files are extra storage.files entries to merge in. A path that
+	// collides with a file the installer already generates is an error.
+	// +optional
+	Files []IgnitionFile `json:"files,omitempty"`
+
+	// Units are extra systemd.units entries to merge in. A unit whose
+	// name collides with one the installer already generates has its
+	// contents merged according to DropinMerge rather than rejected.
+	// +optional
+	Units []IgnitionUnit `json:"units,omitempty"`
+
+	// Users are extra passwd.users entries to merge in.
+	// +optional
+	Users []IgnitionUser `json:"users,omitempty"`
+}
+
+// IgnitionFile is a single storage.files entry to merge into a
+// generated Ignition config.
+type IgnitionFile struct {
+	// Path is the absolute path the file is written to.
+	Path string `json:"path"`
+
+	// Mode is the file's permission mode. Defaults to 0644.
+	// +optional
+	Mode int `json:"mode,omitempty"`
+
+	// Contents is the literal content of the file.
+	Contents string `json:"contents"`
+}
+
+// IgnitionUnit is a single systemd.units entry to merge into a
+// generated Ignition config.
+type IgnitionUnit struct {
+	// Name is the systemd unit name, e.g. "kubelet.service". To add a
+	// dropin to an installer-generated unit, use that unit's name with
+	// DropinMerge Append.
+	Name string `json:"name"`
+
+	// Contents is the unit file content, or the dropin content when it is
+	// appended to an existing unit.
+	// +optional
+	Contents string `json:"contents,omitempty"`
+
+	// Enabled, if set, overrides whether the unit is enabled.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// DropinMerge controls how Contents is combined with an existing
+	// unit of the same Name that the installer already generates.
+	// Defaults to IgnitionDropinMergeAppend.
+	// +optional
+	DropinMerge IgnitionDropinMergeStrategy `json:"dropinMerge,omitempty"`
+}
+
+// IgnitionDropinMergeStrategy selects how an IgnitionUnit override is
+// combined with an installer-generated unit of the same name.
+type IgnitionDropinMergeStrategy string
+
+const (
+	// IgnitionDropinMergeAppend adds Contents as a new systemd drop-in of
+	// the existing unit, leaving the unit's own contents unchanged. This
+	// is the default.
+	IgnitionDropinMergeAppend IgnitionDropinMergeStrategy = "Append"
+	// IgnitionDropinMergeReplace replaces the existing unit's contents
+	// with Contents.
+	IgnitionDropinMergeReplace IgnitionDropinMergeStrategy = "Replace"
+)
+
+// IgnitionUser is a single passwd.users entry to merge into a generated
+// Ignition config.
+type IgnitionUser struct {
+	// Name is the username.
+	Name string `json:"name"`
+
+	// SSHAuthorizedKeys are the SSH public keys authorized for Name.
+	// +optional
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+}
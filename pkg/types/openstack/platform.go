@@ -0,0 +1,47 @@
+package openstack
+
+// Platform stores all the global configuration that all machinesets use
+// when installing on OpenStack.
+type Platform struct {
+	// Cloud is the name of the OpenStack cloud to use from clouds.yaml.
+	Cloud string `json:"cloud"`
+
+	// Region specifies the OpenStack region where the cluster will be
+	// created.
+	Region string `json:"region"`
+
+	// ExternalNetwork is the name of the external network to use for
+	// access to the Internet.
+	// +optional
+	ExternalNetwork string `json:"externalNetwork,omitempty"`
+
+	// FlavorName is the OpenStack flavor used for master and worker
+	// instances.
+	FlavorName string `json:"computeFlavor"`
+
+	// ImageName is the name to use (creating it if it doesn't already
+	// exist) for the RHCOS image uploaded to Glance.
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// SwiftContainer is the name of the Swift container that the
+	// generated bootstrap.ign is uploaded to as a temporary object, so
+	// master instances can fetch it via a TempURL instead of carrying it
+	// in full as instance user-data.
+	// +optional
+	SwiftContainer string `json:"swiftContainer,omitempty"`
+
+	// TempURLKey is the Swift account TempURL key used to sign the
+	// bootstrap.ign TempURL. If empty, the account's existing key is
+	// reused; only when the account has none does the installer generate
+	// one and set it on the account. A non-empty value is not written to
+	// the account.
+	// +optional
+	TempURLKey string `json:"tempURLKey,omitempty"`
+
+	// CloudsYAMLPath is the path to the clouds.yaml file holding the
+	// credentials for Cloud. Defaults to the standard OpenStack SDK
+	// search path when empty.
+	// +optional
+	CloudsYAMLPath string `json:"cloudsYAMLPath,omitempty"`
+}